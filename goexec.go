@@ -23,10 +23,24 @@ type ExecTask struct {
 	// Command contains arguments.
 	Args []string
 
-	// Shell run the command in a bash shell.
-	// Note that the system must have `bash` installed in the PATH or in /bin/bash
+	// Shell runs the command through a shell instead of exec'ing it directly.
+	// The shell used defaults to "sh" on Unix and "cmd.exe" on Windows; set
+	// ShellName (e.g. via WithShell) to "bash", "powershell", "pwsh", "cmd",
+	// or "cmd.exe" to dispatch through a different one.
 	Shell bool
 
+	// ShellName selects the interpreter used when Shell is true. It is
+	// kept separate from Command/Args so that the script or script file
+	// being run never has to double as its own interpreter name. Defaults
+	// to the platform default shell when empty.
+	ShellName string
+
+	// shellScriptFile is set internally by ExecuteShellScript to signal
+	// that Args[0] is a standalone script file to run directly (with
+	// Args[1:] as its own arguments), as opposed to the normal Shell mode
+	// where Command and Args are joined into a single quoted script line.
+	shellScriptFile bool
+
 	// Env is a list of environment variables to add to the current environment,
 	// these are used to override any existing environment variables.
 	Env []string
@@ -63,6 +77,38 @@ type ExecTask struct {
 	OutputFile *os.File
 
 	ErrorFile *os.File
+
+	// GracePeriod is how long to wait after sending TerminationSignal before
+	// escalating to SIGKILL (or, on Windows, a forceful taskkill) when the
+	// context is cancelled or Timeout fires. Zero means kill immediately.
+	GracePeriod time.Duration
+
+	// TerminationSignal is sent to the process group when the context is
+	// cancelled or Timeout fires, before GracePeriod is given to exit
+	// cleanly. Defaults to SIGTERM on Unix and os.Interrupt on Windows.
+	TerminationSignal os.Signal
+
+	// Name labels this task in LineEvent.Task; optional.
+	Name string
+
+	// LineHandler, when set, is called with a LineEvent for every line
+	// written to stdout or stderr as the command runs, letting callers
+	// observe long-running output live instead of waiting for Stdout/
+	// Stderr to be populated once Execute returns.
+	LineHandler func(LineEvent)
+
+	// StderrPrefix, when set, prefixes every stderr line written to
+	// os.Stderr as the command runs.
+	StderrPrefix string
+
+	// JSONLogWriter, when set, receives one JSON-encoded LineEvent per
+	// line of stdout/stderr output, suitable for ingestion by log
+	// pipelines.
+	JSONLogWriter io.Writer
+
+	// Retry configures Execute to retry the task on transient failures.
+	// The zero value runs the task once, with no retries.
+	Retry Retry
 }
 
 type ExecResult struct {
@@ -72,9 +118,30 @@ type ExecResult struct {
 	Timedout  bool
 	Cancelled bool
 	Duration  time.Duration
+
+	// TerminatedBy is set to the signal sent to the process group if the
+	// context was cancelled or Timeout fired, and nil otherwise.
+	TerminatedBy os.Signal
+
+	// KilledAfterGrace reports whether the process had to be forcibly
+	// killed after GracePeriod elapsed following TerminatedBy.
+	KilledAfterGrace bool
+
+	// Attempts is how many times the task was run, including retries
+	// driven by ExecTask.Retry. It is 1 when Retry is unset.
+	Attempts int
+
+	// AttemptHistory holds the result of every attempt, in order, when
+	// ExecTask.Retry caused more than one. It is nil when Retry is unset.
+	AttemptHistory []ExecResult
 }
 
+// Execute runs the task, retrying it per ExecTask.Retry when set.
 func (et ExecTask) Execute(ctx context.Context) (ExecResult, error) {
+	return executeWithRetry(ctx, et)
+}
+
+func (et ExecTask) execute(ctx context.Context) (ExecResult, error) {
 	// If a timeout is set, create a new context with the timeout
 	if et.Timeout > 0 {
 		var cancel context.CancelFunc
@@ -102,28 +169,7 @@ func (et ExecTask) Execute(ctx context.Context) (ExecResult, error) {
 	var command string
 	var commandArgs []string
 	if et.Shell {
-
-		// On a NixOS system, /bin/bash doesn't exist at /bin/bash
-		// the default behavior of exec.Command is to look for the
-		// executable in PATH.
-
-		command = "sh"
-		// There is a chance that PATH is not populate or propagated, therefore
-		// when bash cannot be resolved, set it to /bin/bash instead.
-		if _, err := exec.LookPath(command); err != nil {
-			command = "/usr/bin/sh"
-		}
-
-		if len(et.Args) == 0 {
-			// use Split and Join to remove any extra whitespace?
-			startArgs := strings.Split(et.Command, " ")
-			script := strings.Join(startArgs, " ")
-			commandArgs = append([]string{"-c"}, script)
-
-		} else {
-			script := strings.Join(et.Args, " ")
-			commandArgs = append([]string{"-c"}, fmt.Sprintf("%s %s", et.Command, script))
-		}
+		command, commandArgs = et.shellInvocation()
 	} else {
 
 		command = et.Command
@@ -139,8 +185,12 @@ func (et ExecTask) Execute(ctx context.Context) (ExecResult, error) {
 		// }
 	}
 
-	cmd := exec.CommandContext(ctx, command, commandArgs...)
+	// exec.Command rather than exec.CommandContext: cancellation is handled
+	// by superviseTermination below, which escalates from TerminationSignal
+	// to a forceful kill after GracePeriod instead of killing immediately.
+	cmd := exec.Command(command, commandArgs...)
 	cmd.Dir = et.Cwd
+	setupProcessGroup(cmd)
 
 	if len(et.Env) > 0 {
 		overrides := map[string]bool{}
@@ -193,6 +243,14 @@ func (et ExecTask) Execute(ctx context.Context) (ExecResult, error) {
 		stderrWriters = append(stderrWriters, et.StdErrWriter)
 	}
 
+	var waitLineStreaming func()
+	if et.streamingEnabled() {
+		var stdoutStreamW, stderrStreamW io.Writer
+		stdoutStreamW, stderrStreamW, waitLineStreaming = et.startLineStreaming()
+		stdoutWriters = append(stdoutWriters, stdoutStreamW)
+		stderrWriters = append(stderrWriters, stderrStreamW)
+	}
+
 	cmd.Stdout = io.MultiWriter(stdoutWriters...)
 	cmd.Stderr = io.MultiWriter(stderrWriters...)
 	execStart := time.Now()
@@ -201,9 +259,13 @@ func (et ExecTask) Execute(ctx context.Context) (ExecResult, error) {
 		return ExecResult{}, startErr
 	}
 
-	exitCode := 0
-	execErr := cmd.Wait()
+	execErr, terminatedBy, killedAfterGrace := superviseTermination(ctx, cmd, et.TerminationSignal, et.GracePeriod)
+	if waitLineStreaming != nil {
+		waitLineStreaming()
+	}
 	execEnd := time.Now()
+
+	exitCode := 0
 	if execErr != nil {
 		if exitError, ok := execErr.(*exec.ExitError); ok {
 			exitCode = exitError.ExitCode()
@@ -211,11 +273,13 @@ func (et ExecTask) Execute(ctx context.Context) (ExecResult, error) {
 	}
 
 	return ExecResult{
-		Stdout:    stdoutBuff.String(),
-		Stderr:    stderrBuff.String(),
-		ExitCode:  exitCode,
-		Duration:  execEnd.Sub(execStart),
-		Timedout:  ctx.Err() == context.DeadlineExceeded,
-		Cancelled: ctx.Err() == context.Canceled,
+		Stdout:           stdoutBuff.String(),
+		Stderr:           stderrBuff.String(),
+		ExitCode:         exitCode,
+		Duration:         execEnd.Sub(execStart),
+		Timedout:         ctx.Err() == context.DeadlineExceeded,
+		Cancelled:        ctx.Err() == context.Canceled,
+		TerminatedBy:     terminatedBy,
+		KilledAfterGrace: killedAfterGrace,
 	}, ctx.Err()
 }
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package goexec
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// defaultTerminationSignal is the signal sent to a process group when no
+// ExecTask.TerminationSignal is set.
+func defaultTerminationSignal() os.Signal {
+	return syscall.SIGTERM
+}
+
+// setupProcessGroup starts cmd in its own process group so that
+// terminateProcessGroup/killProcessGroup can signal the whole tree of
+// descendants, not just the immediate child.
+func setupProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminateProcessGroup sends sig to cmd's process group.
+func terminateProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	unixSig, ok := sig.(syscall.Signal)
+	if !ok {
+		unixSig = syscall.SIGTERM
+	}
+	return syscall.Kill(-cmd.Process.Pid, unixSig)
+}
+
+// killProcessGroup forcibly kills cmd's process group with SIGKILL.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
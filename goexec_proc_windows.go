@@ -0,0 +1,31 @@
+//go:build windows
+
+package goexec
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// defaultTerminationSignal is the signal sent to a process tree when no
+// ExecTask.TerminationSignal is set. Windows processes don't receive Unix
+// signals, so this is only used to populate ExecResult.TerminatedBy.
+func defaultTerminationSignal() os.Signal {
+	return os.Interrupt
+}
+
+// setupProcessGroup is a no-op on Windows: process trees are torn down via
+// taskkill /T rather than process group signalling.
+func setupProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcessGroup asks the process tree rooted at cmd to exit via
+// taskkill, without /F, so well-behaved children get a chance to clean up.
+func terminateProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	return exec.Command("taskkill", "/T", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
+
+// killProcessGroup forcibly kills the process tree rooted at cmd.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
@@ -0,0 +1,117 @@
+package goexec
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Retry configures Execute to retry a task when RetryOn reports the result
+// as a transient failure, backing off exponentially between attempts. This
+// is common for provisioner-style workflows such as flaky SSH connections
+// or apt lock contention.
+type Retry struct {
+	// MaxAttempts is the maximum number of times to run the task,
+	// including the first attempt. Zero or one means no retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the second attempt.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. Zero means unbounded.
+	MaxBackoff time.Duration
+
+	// Multiplier scales the backoff after each attempt. Zero or one means
+	// a constant delay of InitialBackoff.
+	Multiplier float64
+
+	// RetryOn decides whether a given attempt's result should be retried.
+	// If nil, no attempt is retried.
+	RetryOn func(ExecResult, error) bool
+}
+
+// RetryOnExitCode returns a Retry.RetryOn function that retries when the
+// task's exit code is one of codes.
+func RetryOnExitCode(codes ...int) func(ExecResult, error) bool {
+	set := make(map[int]bool, len(codes))
+	for _, c := range codes {
+		set[c] = true
+	}
+	return func(result ExecResult, err error) bool {
+		return set[result.ExitCode]
+	}
+}
+
+// RetryOnTimeout returns a Retry.RetryOn function that retries when an
+// attempt was cancelled by ExecTask.Timeout.
+func RetryOnTimeout() func(ExecResult, error) bool {
+	return func(result ExecResult, err error) bool {
+		return result.Timedout
+	}
+}
+
+// backoff computes the delay before attempt (1-indexed: the delay before
+// running attempt 2 is backoff(r, 1)), with full jitter applied so that
+// many retrying callers don't all retry in lockstep.
+func (r Retry) backoff(attempt int) time.Duration {
+	mult := r.Multiplier
+	if mult < 1 {
+		mult = 1
+	}
+
+	d := float64(r.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= mult
+	}
+
+	if r.MaxBackoff > 0 && d > float64(r.MaxBackoff) {
+		d = float64(r.MaxBackoff)
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// Full jitter: a uniformly random delay between 0 and d.
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// executeWithRetry runs task via Execute, retrying up to Retry.MaxAttempts
+// times while Retry.RetryOn reports the result as transient, sleeping with
+// exponential backoff between attempts. It honors ctx cancellation both
+// during an attempt and while sleeping.
+func executeWithRetry(ctx context.Context, task ExecTask) (ExecResult, error) {
+	maxAttempts := task.Retry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var history []ExecResult
+	var result ExecResult
+	var err error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result, err = task.execute(ctx)
+		result.Attempts = attempt
+		history = append(history, result)
+
+		retry := attempt < maxAttempts && task.Retry.RetryOn != nil && task.Retry.RetryOn(result, err)
+		if !retry {
+			break
+		}
+
+		delay := task.Retry.backoff(attempt)
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				result.AttemptHistory = history
+				return result, ctx.Err()
+			}
+		}
+	}
+
+	result.AttemptHistory = history
+	return result, err
+}
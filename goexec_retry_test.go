@@ -0,0 +1,77 @@
+package goexec
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_Execute_RetriesUntilSuccess(t *testing.T) {
+	task := ExecTask{
+		Command: "sh",
+		Args:    []string{"-c", "exit 7"},
+		Retry: Retry{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			RetryOn:        RetryOnExitCode(7),
+		},
+	}
+
+	result, _ := task.Execute(context.Background())
+
+	if result.Attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", result.Attempts)
+	}
+	if len(result.AttemptHistory) != 3 {
+		t.Fatalf("expected 3 entries in AttemptHistory, got %d", len(result.AttemptHistory))
+	}
+	if result.ExitCode != 7 {
+		t.Fatalf("expected final exit code 7, got %d", result.ExitCode)
+	}
+}
+
+func Test_Execute_StopsRetryingOnSuccess(t *testing.T) {
+	task := ExecTask{
+		Command: "sh",
+		Args:    []string{"-c", "exit 0"},
+		Retry: Retry{
+			MaxAttempts:    5,
+			InitialBackoff: time.Millisecond,
+			RetryOn:        RetryOnExitCode(7),
+		},
+	}
+
+	result, _ := task.Execute(context.Background())
+
+	if result.Attempts != 1 {
+		t.Fatalf("expected a single attempt when the first succeeds, got %d", result.Attempts)
+	}
+}
+
+func Test_Execute_NoRetryByDefault(t *testing.T) {
+	task := ExecTask{
+		Command: "sh",
+		Args:    []string{"-c", "exit 1"},
+	}
+
+	result, _ := task.Execute(context.Background())
+
+	if result.Attempts != 1 {
+		t.Fatalf("expected a single attempt with no Retry set, got %d", result.Attempts)
+	}
+}
+
+func Test_Retry_backoff(t *testing.T) {
+	r := Retry{InitialBackoff: 100 * time.Millisecond, Multiplier: 2, MaxBackoff: 300 * time.Millisecond}
+
+	for attempt, max := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 300 * time.Millisecond,
+		4: 300 * time.Millisecond,
+	} {
+		if d := r.backoff(attempt); d > max {
+			t.Errorf("backoff(%d) = %v, want <= %v", attempt, d, max)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package goexec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// TaskState is a state in a Runner task's lifecycle.
+type TaskState int
+
+const (
+	Pending TaskState = iota
+	Running
+	Done
+	Failed
+	Skipped
+)
+
+func (s TaskState) String() string {
+	switch s {
+	case Pending:
+		return "Pending"
+	case Running:
+		return "Running"
+	case Done:
+		return "Done"
+	case Failed:
+		return "Failed"
+	case Skipped:
+		return "Skipped"
+	default:
+		return "Unknown"
+	}
+}
+
+// Progress is a state transition for a single named task, sent on
+// Runner.Progress as a Run unfolds.
+type Progress struct {
+	Name  string
+	State TaskState
+}
+
+// Runner executes a set of named ExecTasks concurrently, honoring
+// dependency edges between them and a configurable parallelism limit, in
+// the spirit of a build system like redo scheduling independent steps in
+// parallel while respecting the dependency graph.
+type Runner struct {
+	// Parallelism caps how many tasks run at once. Zero or negative means
+	// unbounded.
+	Parallelism int
+
+	// BaseEnv is inherited by every task in addition to its own Env, with
+	// the task's own entries taking precedence on conflicting keys.
+	BaseEnv []string
+
+	// ContinueOnError, when true, lets independent branches of the
+	// dependency graph keep running after a task fails; only the tasks
+	// that (transitively) depend on the failed one are skipped. When
+	// false (the default), Run cancels every other in-flight task as soon
+	// as one fails.
+	ContinueOnError bool
+
+	// Progress, when non-nil, receives a Progress value for every state
+	// transition. Run closes it once all tasks have settled. Callers must
+	// drain it concurrently with Run (e.g. from another goroutine), or an
+	// unbuffered/full channel will stall task scheduling.
+	Progress chan Progress
+
+	mu    sync.Mutex
+	nodes map[string]*runnerNode
+	order []string
+}
+
+type runnerNode struct {
+	name string
+	task ExecTask
+	deps []string
+}
+
+// AddTask registers a named ExecTask with the runner, along with the names
+// of any tasks it depends on. deps must already have been added, or be
+// added before Run is called.
+func (r *Runner) AddTask(name string, t ExecTask, deps ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.nodes == nil {
+		r.nodes = map[string]*runnerNode{}
+	}
+	if _, exists := r.nodes[name]; !exists {
+		r.order = append(r.order, name)
+	}
+	r.nodes[name] = &runnerNode{name: name, task: t, deps: deps}
+}
+
+// Run topologically sorts the registered tasks and executes them
+// concurrently, launching each task as soon as its dependencies have
+// completed, up to Parallelism at a time. It returns the ExecResult of
+// every task that ran, and an aggregate error if any task failed.
+func (r *Runner) Run(ctx context.Context) (map[string]ExecResult, error) {
+	r.mu.Lock()
+	nodes := make(map[string]*runnerNode, len(r.nodes))
+	order := append([]string(nil), r.order...)
+	for name, n := range r.nodes {
+		nodes[name] = n
+	}
+	r.mu.Unlock()
+
+	if err := checkDeps(nodes); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, r.semCap())
+
+	var resultsMu sync.Mutex
+	results := make(map[string]ExecResult, len(order))
+
+	done := make(map[string]chan struct{}, len(order))
+	for _, name := range order {
+		done[name] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	var failedMu sync.Mutex
+	var firstErr error
+	failed := map[string]bool{}
+
+	setState := func(name string, s TaskState) {
+		if r.Progress != nil {
+			r.Progress <- Progress{Name: name, State: s}
+		}
+	}
+
+	for _, name := range order {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer close(done[name])
+
+			node := nodes[name]
+			for _, dep := range node.deps {
+				<-done[dep]
+			}
+
+			failedMu.Lock()
+			depFailed := false
+			for _, dep := range node.deps {
+				if failed[dep] {
+					depFailed = true
+					break
+				}
+			}
+			failedMu.Unlock()
+
+			if depFailed || (ctx.Err() != nil && !r.ContinueOnError) {
+				setState(name, Skipped)
+				failedMu.Lock()
+				failed[name] = true
+				failedMu.Unlock()
+				return
+			}
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				setState(name, Skipped)
+				failedMu.Lock()
+				failed[name] = true
+				failedMu.Unlock()
+				return
+			}
+
+			setState(name, Running)
+
+			task := node.task
+			task.Env = mergeEnv(r.BaseEnv, task.Env)
+			if task.Name == "" {
+				task.Name = name
+			}
+
+			result, err := task.Execute(ctx)
+
+			resultsMu.Lock()
+			results[name] = result
+			resultsMu.Unlock()
+
+			if err != nil || result.ExitCode != 0 {
+				setState(name, Failed)
+				failedMu.Lock()
+				failed[name] = true
+				if firstErr == nil {
+					if err != nil {
+						firstErr = fmt.Errorf("task %q: %w", name, err)
+					} else {
+						firstErr = fmt.Errorf("task %q: exit code %d", name, result.ExitCode)
+					}
+				}
+				failedMu.Unlock()
+				if !r.ContinueOnError {
+					cancel()
+				}
+				return
+			}
+
+			setState(name, Done)
+		}(name)
+	}
+
+	wg.Wait()
+	if r.Progress != nil {
+		close(r.Progress)
+	}
+
+	return results, firstErr
+}
+
+func (r *Runner) semCap() int {
+	if r.Parallelism > 0 {
+		return r.Parallelism
+	}
+	return 1 << 20
+}
+
+// checkDeps verifies every dependency name refers to a registered task and
+// that the graph has no cycles, so Run doesn't deadlock waiting on a
+// dependency that will never complete.
+func checkDeps(nodes map[string]*runnerNode) error {
+	for name, n := range nodes {
+		for _, dep := range n.deps {
+			if _, ok := nodes[dep]; !ok {
+				return fmt.Errorf("task %q depends on unknown task %q", name, dep)
+			}
+		}
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(nodes))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle detected at task %q", name)
+		}
+		state[name] = visiting
+		for _, dep := range nodes[name].deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+
+	for name := range nodes {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeEnv returns base overlaid with overrides, with overrides winning on
+// conflicting keys, mirroring how ExecTask.Env already overrides the
+// process environment in Execute.
+func mergeEnv(base, overrides []string) []string {
+	if len(overrides) == 0 {
+		return base
+	}
+
+	keys := map[string]bool{}
+	for _, kv := range overrides {
+		keys[envKey(kv)] = true
+	}
+
+	merged := make([]string, 0, len(base)+len(overrides))
+	for _, kv := range base {
+		if !keys[envKey(kv)] {
+			merged = append(merged, kv)
+		}
+	}
+	return append(merged, overrides...)
+}
+
+func envKey(kv string) string {
+	for i := 0; i < len(kv); i++ {
+		if kv[i] == '=' {
+			return kv[:i]
+		}
+	}
+	return kv
+}
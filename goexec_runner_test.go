@@ -0,0 +1,72 @@
+package goexec
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Runner_RunsInDependencyOrder(t *testing.T) {
+	r := &Runner{}
+	r.AddTask("a", ExecTask{Command: "sh", Args: []string{"-c", "true"}})
+	r.AddTask("b", ExecTask{Command: "sh", Args: []string{"-c", "true"}}, "a")
+	r.AddTask("c", ExecTask{Command: "sh", Args: []string{"-c", "true"}}, "a")
+	r.AddTask("d", ExecTask{Command: "sh", Args: []string{"-c", "true"}}, "b", "c")
+
+	results, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		if _, ok := results[name]; !ok {
+			t.Fatalf("missing result for %q", name)
+		}
+	}
+}
+
+func Test_Runner_FailurePropagatesToDependents(t *testing.T) {
+	r := &Runner{}
+	r.AddTask("a", ExecTask{Command: "sh", Args: []string{"-c", "exit 1"}})
+	r.AddTask("b", ExecTask{Command: "sh", Args: []string{"-c", "true"}}, "a")
+
+	_, err := r.Run(context.Background())
+	if err == nil {
+		t.Fatalf("expected an error when a dependency fails")
+	}
+}
+
+func Test_Runner_ContinueOnError(t *testing.T) {
+	r := &Runner{ContinueOnError: true}
+	r.AddTask("a", ExecTask{Command: "sh", Args: []string{"-c", "exit 1"}})
+	r.AddTask("b", ExecTask{Command: "sh", Args: []string{"-c", "true"}})
+
+	results, err := r.Run(context.Background())
+	if err == nil {
+		t.Fatalf("expected an aggregate error")
+	}
+	if results["b"].ExitCode != 0 {
+		t.Fatalf("expected independent task b to still run and succeed")
+	}
+}
+
+func Test_Runner_UnknownDependency(t *testing.T) {
+	r := &Runner{}
+	r.AddTask("a", ExecTask{Command: "sh", Args: []string{"-c", "true"}}, "missing")
+
+	if _, err := r.Run(context.Background()); err == nil {
+		t.Fatalf("expected an error for an unknown dependency")
+	}
+}
+
+func Test_Runner_DependencyCycle(t *testing.T) {
+	r := &Runner{}
+	r.AddTask("a", ExecTask{Command: "sh", Args: []string{"-c", "true"}}, "b")
+	r.AddTask("b", ExecTask{Command: "sh", Args: []string{"-c", "true"}}, "a")
+
+	if _, err := r.Run(context.Background()); err == nil {
+		t.Fatalf("expected an error for a dependency cycle")
+	}
+}
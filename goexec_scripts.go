@@ -3,6 +3,7 @@ package goexec
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -17,7 +18,7 @@ func WithEnv(env []string) ShellScriptOption {
 func WithShell(shell string) ShellScriptOption {
 	return func(et *ExecTask) {
 		if shell != "" {
-			et.Command = shell
+			et.ShellName = shell
 			et.Shell = true
 		}
 	}
@@ -42,17 +43,38 @@ func WithOutputFiles(stdout, stderr *os.File) ShellScriptOption {
 	}
 }
 
+func WithLineHandler(handler func(LineEvent)) ShellScriptOption {
+	return func(et *ExecTask) {
+		et.LineHandler = handler
+	}
+}
+
+func WithStderrPrefix(prefix string) ShellScriptOption {
+	return func(et *ExecTask) {
+		et.StderrPrefix = prefix
+	}
+}
+
+func WithJSONLogWriter(w io.Writer) ShellScriptOption {
+	return func(et *ExecTask) {
+		et.JSONLogWriter = w
+	}
+}
+
 func ExecuteShellScript(ctx context.Context, scriptPath string, opts ...ShellScriptOption) (ExecResult, error) {
 	// Check if the script exists
 	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
 		return ExecResult{}, fmt.Errorf("script not found: %s", scriptPath)
 	}
 
-	// Default ExecTask setup
+	// Default ExecTask setup: pick the interpreter from the script's
+	// extension (.ps1, .bat, .cmd, .sh) so scripts run under their native
+	// shell; WithShell can still override this.
 	execTask := ExecTask{
-		Command: "sh", // Default shell
-		Args:    []string{scriptPath},
-		Shell:   true, // Ensure it runs in a shell
+		ShellName:       shellKindName(scriptShellKind(scriptPath)),
+		Args:            []string{scriptPath},
+		Shell:           true, // Ensure it runs in a shell
+		shellScriptFile: true, // Args[0] is the script file itself, not arguments to join with Command
 	}
 
 	// Apply options (will override defaults if provided)
@@ -0,0 +1,193 @@
+package goexec
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// shellKind identifies which interpreter a Shell-mode ExecTask should be
+// run through.
+type shellKind int
+
+const (
+	shellPOSIX shellKind = iota
+	shellCmd
+	shellPowerShell
+)
+
+// resolveShellKind maps the shell name given to WithShell (or
+// ExecTask.ShellName) to a shellKind, falling back to the platform default
+// when name is empty.
+func resolveShellKind(name string) shellKind {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "powershell", "pwsh":
+		return shellPowerShell
+	case "cmd", "cmd.exe":
+		return shellCmd
+	case "sh", "bash", "":
+		if name == "" {
+			return defaultShellKind()
+		}
+		return shellPOSIX
+	default:
+		return shellPOSIX
+	}
+}
+
+// defaultShellKind picks the shell used when Shell is true but no explicit
+// shell was requested via WithShell.
+func defaultShellKind() shellKind {
+	if runtime.GOOS == "windows" {
+		return shellCmd
+	}
+	return shellPOSIX
+}
+
+// shellInvocation builds the program and argv needed to run et through a
+// shell, without routing an already-resolved interpreter back through a
+// second layer of shell quoting.
+//
+// The normal case (et.shellScriptFile false, e.g. ExecTask{Shell: true,
+// Command: "echo", Args: []string{"hello world"}}) joins et.Command and
+// et.Args into a single quoted script line and hands it to the interpreter
+// as script text, exactly as if et.Command alone had contained that quoted
+// line. When et.shellScriptFile is true (set internally by
+// ExecuteShellScript), et.Args[0] is instead a standalone script file run
+// directly with et.Args[1:] as its own arguments; exec.Command's normal
+// per-argument handling (execve argv on Unix, automatic command-line
+// escaping on Windows) takes care of quoting in that case, so et.Command is
+// ignored.
+func (et ExecTask) shellInvocation() (program string, cmdArgs []string) {
+	kind := resolveShellKind(et.ShellName)
+
+	if et.shellScriptFile {
+		switch kind {
+		case shellPowerShell:
+			return powershellProgram(et.ShellName), append([]string{"-NoProfile", "-File"}, et.Args...)
+		case shellCmd:
+			return "cmd.exe", append([]string{"/C"}, et.Args...)
+		default:
+			return posixProgram(et.ShellName), et.Args
+		}
+	}
+
+	script := et.Command
+	if len(et.Args) > 0 {
+		script = joinShellArgs(kind, et.Command, et.Args)
+	}
+
+	switch kind {
+	case shellPowerShell:
+		return powershellProgram(et.ShellName), []string{"-NoProfile", "-Command", script}
+	case shellCmd:
+		return "cmd.exe", []string{"/C", script}
+	default:
+		return posixProgram(et.ShellName), []string{"-c", script}
+	}
+}
+
+// joinShellArgs joins command and its arguments into a single script string,
+// quoting each argument so that spaces and quotes within an argument aren't
+// mangled by the receiving shell.
+func joinShellArgs(kind shellKind, command string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	if command != "" {
+		parts = append(parts, command)
+	}
+	for _, a := range args {
+		parts = append(parts, quoteShellArg(kind, a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteShellArg quotes a single argument for the target shell. Arguments
+// that need no quoting are returned unchanged to keep simple commands
+// readable.
+func quoteShellArg(kind shellKind, arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'$`\\") {
+		return arg
+	}
+
+	switch kind {
+	case shellCmd:
+		// cmd.exe has no consistent escaping for embedded quotes; wrapping
+		// in double quotes and doubling any existing ones is the closest
+		// to a sane default and matches what most built-ins accept.
+		return `"` + strings.ReplaceAll(arg, `"`, `""`) + `"`
+	case shellPowerShell:
+		// PowerShell single-quoted strings are literal; escape embedded
+		// single quotes by doubling them.
+		return "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+	default:
+		// POSIX single-quoted strings are literal; embedded single quotes
+		// must be closed, escaped, and reopened.
+		return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+}
+
+// posixProgram resolves the POSIX shell executable named by shellName,
+// defaulting to "sh" when shellName is empty or not "bash".
+func posixProgram(shellName string) string {
+	program := "sh"
+	if strings.ToLower(strings.TrimSpace(shellName)) == "bash" {
+		program = "bash"
+	}
+
+	if _, err := exec.LookPath(program); err != nil {
+		// On a NixOS system, sh/bash don't live at their usual absolute
+		// paths; the default behavior of exec.Command is to look for the
+		// executable in PATH, and there is a chance that PATH is not
+		// populated or propagated, so fall back to the absolute path.
+		switch program {
+		case "bash":
+			return "/bin/bash"
+		default:
+			return "/usr/bin/sh"
+		}
+	}
+	return program
+}
+
+// powershellProgram resolves the PowerShell executable named by shellName.
+// "pwsh" (PowerShell Core, the only PowerShell available on Linux/macOS)
+// and "powershell" (Windows PowerShell) are distinct binaries, so an
+// explicit request for "pwsh" is kept verbatim instead of being silently
+// run as "powershell".
+func powershellProgram(shellName string) string {
+	if strings.ToLower(strings.TrimSpace(shellName)) == "pwsh" {
+		return "pwsh"
+	}
+	return "powershell"
+}
+
+// scriptShellKind picks the interpreter for ExecuteShellScript based on the
+// script's file extension, so that .ps1/.bat/.cmd/.sh scripts run under
+// their native interpreter by default.
+func scriptShellKind(scriptPath string) shellKind {
+	switch strings.ToLower(filepath.Ext(scriptPath)) {
+	case ".ps1":
+		return shellPowerShell
+	case ".bat", ".cmd":
+		return shellCmd
+	case ".sh":
+		return shellPOSIX
+	default:
+		return defaultShellKind()
+	}
+}
+
+// shellKindName returns the WithShell name for kind, used to populate
+// ExecTask.ShellName when ExecuteShellScript infers a shell from a script
+// extension.
+func shellKindName(kind shellKind) string {
+	switch kind {
+	case shellPowerShell:
+		return "powershell"
+	case shellCmd:
+		return "cmd"
+	default:
+		return "sh"
+	}
+}
@@ -0,0 +1,174 @@
+package goexec
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Execute_ShellCommandWithArgs(t *testing.T) {
+	task := ExecTask{
+		Shell:   true,
+		Command: "echo",
+		Args:    []string{"hello", "world"},
+	}
+
+	result, err := task.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %q)", result.ExitCode, result.Stderr)
+	}
+	if want := "hello world\n"; result.Stdout != want {
+		t.Errorf("stdout = %q, want %q", result.Stdout, want)
+	}
+}
+
+func Test_resolveShellKind(t *testing.T) {
+	cases := []struct {
+		name string
+		want shellKind
+	}{
+		{"sh", shellPOSIX},
+		{"bash", shellPOSIX},
+		{"cmd", shellCmd},
+		{"cmd.exe", shellCmd},
+		{"powershell", shellPowerShell},
+		{"pwsh", shellPowerShell},
+		{"PowerShell", shellPowerShell},
+	}
+
+	for _, c := range cases {
+		if got := resolveShellKind(c.name); got != c.want {
+			t.Errorf("resolveShellKind(%q) = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func Test_scriptShellKind(t *testing.T) {
+	cases := []struct {
+		path string
+		want shellKind
+	}{
+		{"deploy.ps1", shellPowerShell},
+		{"deploy.bat", shellCmd},
+		{"deploy.cmd", shellCmd},
+		{"deploy.sh", shellPOSIX},
+	}
+
+	for _, c := range cases {
+		if got := scriptShellKind(c.path); got != c.want {
+			t.Errorf("scriptShellKind(%q) = %v, want %v", c.path, got, c.want)
+		}
+	}
+}
+
+func Test_quoteShellArg(t *testing.T) {
+	cases := []struct {
+		kind shellKind
+		arg  string
+		want string
+	}{
+		{shellPOSIX, "hello", "hello"},
+		{shellPOSIX, "hello world", "'hello world'"},
+		{shellPOSIX, "it's", `'it'\''s'`},
+		{shellCmd, "hello world", `"hello world"`},
+		{shellCmd, `say "hi"`, `"say ""hi"""`},
+		{shellPowerShell, "hello world", "'hello world'"},
+		{shellPowerShell, "it's", "'it''s'"},
+	}
+
+	for _, c := range cases {
+		if got := quoteShellArg(c.kind, c.arg); got != c.want {
+			t.Errorf("quoteShellArg(%v, %q) = %q, want %q", c.kind, c.arg, got, c.want)
+		}
+	}
+}
+
+func Test_joinShellArgs(t *testing.T) {
+	got := joinShellArgs(shellPOSIX, "echo", []string{"hello world", "plain"})
+	want := "echo 'hello world' plain"
+	if got != want {
+		t.Errorf("joinShellArgs(...) = %q, want %q", got, want)
+	}
+}
+
+func Test_ExecTask_shellInvocation(t *testing.T) {
+	cases := []struct {
+		name    string
+		task    ExecTask
+		program string
+		cmdArgs []string
+	}{
+		{
+			name:    "posix command+args joined into a script line",
+			task:    ExecTask{Shell: true, Command: "echo", Args: []string{"hello world"}},
+			program: posixProgram(""),
+			cmdArgs: []string{"-c", "echo 'hello world'"},
+		},
+		{
+			name:    "powershell script text",
+			task:    ExecTask{Shell: true, ShellName: "powershell", Command: "Get-Date"},
+			program: "powershell",
+			cmdArgs: []string{"-NoProfile", "-Command", "Get-Date"},
+		},
+		{
+			name:    "powershell command+args joined into a script line",
+			task:    ExecTask{Shell: true, ShellName: "powershell", Command: "Write-Output", Args: []string{"hello world"}},
+			program: "powershell",
+			cmdArgs: []string{"-NoProfile", "-Command", "Write-Output 'hello world'"},
+		},
+		{
+			name:    "pwsh script text keeps the pwsh binary name",
+			task:    ExecTask{Shell: true, ShellName: "pwsh", Command: "Get-Date"},
+			program: "pwsh",
+			cmdArgs: []string{"-NoProfile", "-Command", "Get-Date"},
+		},
+		{
+			name:    "pwsh script file keeps the pwsh binary name",
+			task:    ExecTask{Shell: true, ShellName: "pwsh", Args: []string{"/scripts/deploy.ps1", "-Verbose"}, shellScriptFile: true},
+			program: "pwsh",
+			cmdArgs: []string{"-NoProfile", "-File", "/scripts/deploy.ps1", "-Verbose"},
+		},
+		{
+			name:    "cmd command+args joined into a script line",
+			task:    ExecTask{Shell: true, ShellName: "cmd", Command: "echo", Args: []string{"hello world"}},
+			program: "cmd.exe",
+			cmdArgs: []string{"/C", `echo "hello world"`},
+		},
+		{
+			name:    "powershell script file",
+			task:    ExecTask{Shell: true, ShellName: "powershell", Args: []string{`C:\scripts\deploy.ps1`, "-Verbose"}, shellScriptFile: true},
+			program: "powershell",
+			cmdArgs: []string{"-NoProfile", "-File", `C:\scripts\deploy.ps1`, "-Verbose"},
+		},
+		{
+			name:    "cmd script file",
+			task:    ExecTask{Shell: true, ShellName: "cmd", Args: []string{`C:\scripts\deploy.bat`, "prod"}, shellScriptFile: true},
+			program: "cmd.exe",
+			cmdArgs: []string{"/C", `C:\scripts\deploy.bat`, "prod"},
+		},
+	}
+
+	for _, c := range cases {
+		program, cmdArgs := c.task.shellInvocation()
+		if program != c.program {
+			t.Errorf("%s: program = %q, want %q", c.name, program, c.program)
+		}
+		if !slicesEqual(cmdArgs, c.cmdArgs) {
+			t.Errorf("%s: cmdArgs = %v, want %v", c.name, cmdArgs, c.cmdArgs)
+		}
+	}
+}
+
+func slicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
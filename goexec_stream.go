@@ -0,0 +1,159 @@
+package goexec
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// LineEvent describes a single line of output captured while streaming, via
+// ExecTask.LineHandler or ExecTask.JSONLogWriter.
+type LineEvent struct {
+	// Stream is "stdout" or "stderr".
+	Stream string
+
+	// Line is the line's text, with the trailing newline stripped.
+	Line string
+
+	// Time is when the line was read.
+	Time time.Time
+
+	// Task is ExecTask.Name, letting callers tell lines from different
+	// tasks apart when handlers are shared across several ExecTasks.
+	Task string
+
+	// Err is set, with Line empty, when the stream stopped scanning early
+	// (e.g. bufio.ErrTooLong for a line past the 1MB max buffer, or a pipe
+	// read error) instead of reaching EOF normally. Callers relying on
+	// LineHandler/JSONLogWriter to have observed output live should treat
+	// this as notice that the rest of that stream was not captured.
+	Err error
+}
+
+// MarshalJSON encodes e for JSONLogWriter, rendering Err as its error
+// message since the error interface has nothing for encoding/json to
+// marshal on its own.
+func (e LineEvent) MarshalJSON() ([]byte, error) {
+	type alias struct {
+		Stream string    `json:"Stream"`
+		Line   string    `json:"Line"`
+		Time   time.Time `json:"Time"`
+		Task   string    `json:"Task"`
+		Err    string    `json:"Err,omitempty"`
+	}
+	a := alias{Stream: e.Stream, Line: e.Line, Time: e.Time, Task: e.Task}
+	if e.Err != nil {
+		a.Err = e.Err.Error()
+	}
+	return json.Marshal(a)
+}
+
+// lineStreamer scans lines from a stream as it runs and turns them into
+// LineEvents for ExecTask.LineHandler/StderrPrefix/JSONLogWriter, without
+// paying the memory cost of buffering the whole output in ExecResult.
+type lineStreamer struct {
+	et     *ExecTask
+	stream string
+
+	jsonMu *sync.Mutex
+}
+
+func (s *lineStreamer) run(r io.Reader, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	scanner := bufio.NewScanner(r)
+	// Long lines (e.g. minified JSON logs) shouldn't be silently truncated.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		s.handleLine(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		s.handleErr(err)
+		// The scanner gave up on r, but the command writing to the other
+		// end of the pipe hasn't necessarily finished; drain the rest so
+		// it doesn't block forever on a write nobody is reading.
+		io.Copy(io.Discard, r)
+	}
+}
+
+func (s *lineStreamer) handleLine(line string) {
+	evt := LineEvent{
+		Stream: s.stream,
+		Line:   line,
+		Time:   time.Now(),
+		Task:   s.et.Name,
+	}
+
+	if s.et.LineHandler != nil {
+		s.et.LineHandler(evt)
+	}
+
+	if s.stream == "stderr" && s.et.StderrPrefix != "" {
+		fmt.Fprintf(os.Stderr, "%s%s\n", s.et.StderrPrefix, line)
+	}
+
+	if s.et.JSONLogWriter != nil {
+		s.jsonMu.Lock()
+		_ = json.NewEncoder(s.et.JSONLogWriter).Encode(evt)
+		s.jsonMu.Unlock()
+	}
+}
+
+// handleErr reports that scanning stopped before EOF, so callers relying on
+// LineHandler/JSONLogWriter for live output know the rest of the stream was
+// not captured.
+func (s *lineStreamer) handleErr(err error) {
+	evt := LineEvent{
+		Stream: s.stream,
+		Time:   time.Now(),
+		Task:   s.et.Name,
+		Err:    err,
+	}
+
+	if s.et.LineHandler != nil {
+		s.et.LineHandler(evt)
+	}
+
+	if s.stream == "stderr" && s.et.StderrPrefix != "" {
+		fmt.Fprintf(os.Stderr, "%s[stream error: %v]\n", s.et.StderrPrefix, err)
+	}
+
+	if s.et.JSONLogWriter != nil {
+		s.jsonMu.Lock()
+		_ = json.NewEncoder(s.et.JSONLogWriter).Encode(evt)
+		s.jsonMu.Unlock()
+	}
+}
+
+// streamingEnabled reports whether et requested any of the line-streaming
+// features, so Execute can skip the extra pipes/goroutines otherwise.
+func (et *ExecTask) streamingEnabled() bool {
+	return et.LineHandler != nil || et.StderrPrefix != "" || et.JSONLogWriter != nil
+}
+
+// startLineStreaming wires up a pipe per stream that feeds a lineStreamer
+// goroutine, returning the pipe writers to add to stdoutWriters/
+// stderrWriters and a function the caller must invoke once the command has
+// finished writing, to close the pipes and await the streaming goroutines.
+func (et *ExecTask) startLineStreaming() (stdoutW, stderrW io.Writer, wait func()) {
+	var wg sync.WaitGroup
+	var jsonMu sync.Mutex
+
+	stdoutR, stdoutPipeW := io.Pipe()
+	stderrR, stderrPipeW := io.Pipe()
+
+	wg.Add(2)
+	go (&lineStreamer{et: et, stream: "stdout", jsonMu: &jsonMu}).run(stdoutR, &wg)
+	go (&lineStreamer{et: et, stream: "stderr", jsonMu: &jsonMu}).run(stderrR, &wg)
+
+	return stdoutPipeW, stderrPipeW, func() {
+		stdoutPipeW.Close()
+		stderrPipeW.Close()
+		wg.Wait()
+	}
+}
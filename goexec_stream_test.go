@@ -0,0 +1,120 @@
+package goexec
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func Test_Execute_WithLineHandler(t *testing.T) {
+	var mu sync.Mutex
+	var stdoutLines, stderrLines []string
+
+	task := ExecTask{
+		Command: "sh",
+		Args:    []string{"-c", "echo out1; echo out2; echo err1 1>&2"},
+		LineHandler: func(evt LineEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			if evt.Stream == "stdout" {
+				stdoutLines = append(stdoutLines, evt.Line)
+			} else {
+				stderrLines = append(stderrLines, evt.Line)
+			}
+		},
+	}
+
+	result, err := task.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(stdoutLines) != 2 || stdoutLines[0] != "out1" || stdoutLines[1] != "out2" {
+		t.Fatalf("unexpected stdout lines: %v", stdoutLines)
+	}
+	if len(stderrLines) != 1 || stderrLines[0] != "err1" {
+		t.Fatalf("unexpected stderr lines: %v", stderrLines)
+	}
+
+	// The regular buffered output should still be populated.
+	if result.Stdout != "out1\nout2\n" {
+		t.Fatalf("unexpected buffered stdout: %q", result.Stdout)
+	}
+}
+
+func Test_Execute_LineHandler_ReportsScanErrOnTooLongLine(t *testing.T) {
+	var mu sync.Mutex
+	var errEvt *LineEvent
+
+	task := ExecTask{
+		Shell:   true,
+		Command: "head",
+		Args:    []string{"-c", "2000000", "/dev/zero"},
+		LineHandler: func(evt LineEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			if evt.Err != nil {
+				e := evt
+				errEvt = &e
+			}
+		},
+	}
+
+	if _, err := task.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if errEvt == nil {
+		t.Fatal("expected a LineEvent with Err set for a line exceeding the scanner's max buffer")
+	}
+	if errEvt.Stream != "stdout" {
+		t.Fatalf("unexpected stream on error event: %q", errEvt.Stream)
+	}
+}
+
+func Test_LineEvent_MarshalJSON_RendersErrAsString(t *testing.T) {
+	evt := LineEvent{Stream: "stdout", Task: "greet", Err: bufio.ErrTooLong}
+
+	data, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded struct {
+		Err string
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Err != bufio.ErrTooLong.Error() {
+		t.Fatalf("Err = %q, want %q", decoded.Err, bufio.ErrTooLong.Error())
+	}
+}
+
+func Test_Execute_WithJSONLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	task := ExecTask{
+		Command:       "sh",
+		Args:          []string{"-c", "echo hello"},
+		Name:          "greet",
+		JSONLogWriter: &buf,
+	}
+
+	if _, err := task.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	var evt LineEvent
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &evt); err != nil {
+		t.Fatalf("failed to decode JSON log line: %v (data: %q)", err, buf.String())
+	}
+	if evt.Line != "hello" || evt.Stream != "stdout" || evt.Task != "greet" {
+		t.Fatalf("unexpected LineEvent: %+v", evt)
+	}
+}
@@ -0,0 +1,45 @@
+package goexec
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// superviseTermination waits for cmd to exit or for ctx to be done. When ctx
+// is done first (Timeout elapsed or the caller cancelled it), it escalates:
+// the termination signal is sent to the process group, and if the process
+// hasn't exited within GracePeriod, the group is killed outright. It returns
+// once cmd has actually exited.
+func superviseTermination(ctx context.Context, cmd *exec.Cmd, sig os.Signal, gracePeriod time.Duration) (waitErr error, terminatedBy os.Signal, killedAfterGrace bool) {
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case waitErr = <-done:
+		return waitErr, nil, false
+	case <-ctx.Done():
+	}
+
+	if sig == nil {
+		sig = defaultTerminationSignal()
+	}
+	terminatedBy = sig
+	_ = terminateProcessGroup(cmd, sig)
+
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+
+	select {
+	case waitErr = <-done:
+		return waitErr, terminatedBy, false
+	case <-timer.C:
+		killedAfterGrace = true
+		_ = killProcessGroup(cmd)
+		waitErr = <-done
+		return waitErr, terminatedBy, killedAfterGrace
+	}
+}
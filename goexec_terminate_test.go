@@ -0,0 +1,56 @@
+//go:build !windows
+
+package goexec
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func Test_Execute_GracePeriodThenKill(t *testing.T) {
+	task := ExecTask{
+		Command:           "sh",
+		Args:              []string{"-c", "trap '' TERM; sleep 5"},
+		GracePeriod:       200 * time.Millisecond,
+		TerminationSignal: syscall.SIGTERM,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, _ := task.Execute(ctx)
+	elapsed := time.Since(start)
+
+	if result.TerminatedBy != syscall.SIGTERM {
+		t.Fatalf("expected TerminatedBy SIGTERM, got %v", result.TerminatedBy)
+	}
+	if !result.KilledAfterGrace {
+		t.Fatalf("expected KilledAfterGrace to be true")
+	}
+	if elapsed > 2*time.Second {
+		t.Fatalf("expected the process to be killed shortly after the grace period, took %v", elapsed)
+	}
+}
+
+func Test_Execute_TerminatesWithoutGraceKill(t *testing.T) {
+	task := ExecTask{
+		Command:     "sh",
+		Args:        []string{"-c", "sleep 5"},
+		GracePeriod: 2 * time.Second,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	result, _ := task.Execute(ctx)
+
+	if result.TerminatedBy == nil {
+		t.Fatalf("expected TerminatedBy to be set")
+	}
+	if result.KilledAfterGrace {
+		t.Fatalf("expected the process to exit from the termination signal, not a forceful kill")
+	}
+}
@@ -0,0 +1,34 @@
+// Command goexec-scripttest runs one or more txtar script archives
+// standalone, outside of `go test`, using the scripttest engine.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/taskserver/goexec/scripttest"
+)
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: goexec-scripttest <archive.txt> [archive.txt ...]")
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, path := range flag.Args() {
+		if err := scripttest.RunStandalone(path); err != nil {
+			fmt.Printf("FAIL %s: %v\n", path, err)
+			failed = true
+			continue
+		}
+		fmt.Printf("ok   %s\n", path)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
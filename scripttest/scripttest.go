@@ -0,0 +1,338 @@
+// Package scripttest provides a txtar-based script test harness for
+// goexec.ExecTask. Test scenarios are described as txtar archives: a
+// "files" section that is materialized into a temporary directory, and a
+// trailing script of commands that drive ExecTask against that directory.
+//
+// The supported commands borrow their syntax from Go's own cmd/go script
+// tests and rogpeppe/go-internal/testscript:
+//
+//	env KEY=VAL        set an environment variable for subsequent exec lines
+//	cd DIR             change the working directory (relative to the archive root)
+//	exec CMD ARG...    run CMD via ExecTask and capture its result
+//	! exec CMD ARG...  like exec, but the command is expected to fail
+//	stdin FILE         connect FILE (relative to the current dir) to the next exec's stdin
+//	stdout REGEXP      assert the last exec's stdout matches REGEXP
+//	stderr REGEXP      assert the last exec's stderr matches REGEXP
+//	wait DURATION      sleep for DURATION before continuing
+//	sleep DURATION     alias for wait
+//
+// Lines beginning with # are comments and blank lines are ignored.
+package scripttest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/txtar"
+
+	"github.com/taskserver/goexec"
+)
+
+// Run parses the txtar archive at archivePath, materializes its file
+// section into a temporary directory, and executes its script section
+// against goexec.ExecTask. Run calls t.Fatalf on any assertion failure or
+// unexpected error.
+func Run(t *testing.T, archivePath string) {
+	t.Helper()
+
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatalf("scripttest: reading archive %s: %v", archivePath, err)
+	}
+
+	if err := runArchive(data, t.TempDir()); err != nil {
+		t.Fatalf("scripttest: %s: %v", archivePath, err)
+	}
+}
+
+// RunStandalone runs the txtar archive at archivePath outside of `go test`,
+// materializing its files into a freshly created and removed temporary
+// directory. It is used by cmd/goexec-scripttest.
+func RunStandalone(archivePath string) error {
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		return fmt.Errorf("reading archive %s: %w", archivePath, err)
+	}
+
+	dir, err := os.MkdirTemp("", "goexec-scripttest-")
+	if err != nil {
+		return fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	return runArchive(data, dir)
+}
+
+// runArchive materializes archiveData's file section into dir and executes
+// its script section against goexec.ExecTask. It has no dependency on
+// *testing.T so it can also be driven from the standalone CLI.
+func runArchive(archiveData []byte, dir string) error {
+	archive := txtar.Parse(archiveData)
+
+	for _, f := range archive.Files {
+		path := filepath.Join(dir, f.Name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("creating directory for %s: %w", f.Name, err)
+		}
+		if err := os.WriteFile(path, f.Data, 0o644); err != nil {
+			return fmt.Errorf("writing file %s: %w", f.Name, err)
+		}
+	}
+
+	e := &engine{
+		rootDir: dir,
+		cwd:     dir,
+		env:     os.Environ(),
+	}
+
+	return e.runScript(string(archive.Comment))
+}
+
+// engine holds the state threaded through a single archive's script:
+// the current working directory, accumulated environment overrides, and
+// the most recent exec result for stdout/stderr assertions.
+type engine struct {
+	rootDir string
+	cwd     string
+	env     []string
+
+	lastResult goexec.ExecResult
+	lastErr    error
+	nextStdin  string
+}
+
+func (e *engine) runScript(script string) error {
+	lineNo := 0
+	scanner := bufio.NewScanner(strings.NewReader(script))
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := e.runLine(line); err != nil {
+			return fmt.Errorf("line %d: %q: %w", lineNo, line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func (e *engine) runLine(line string) error {
+	negate := false
+	if strings.HasPrefix(line, "!") {
+		negate = true
+		line = strings.TrimSpace(strings.TrimPrefix(line, "!"))
+	}
+
+	cmd, rest := splitCommand(line)
+
+	switch cmd {
+	case "env":
+		return e.cmdEnv(rest)
+	case "cd":
+		return e.cmdCd(rest)
+	case "exec":
+		return e.cmdExec(rest, negate)
+	case "stdin":
+		return e.cmdStdin(rest)
+	case "stdout":
+		return e.cmdStdout(rest, negate)
+	case "stderr":
+		return e.cmdStderr(rest, negate)
+	case "wait", "sleep":
+		return e.cmdWait(rest)
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+func (e *engine) cmdEnv(rest string) error {
+	if rest == "" {
+		return fmt.Errorf("env requires KEY=VALUE")
+	}
+	key := strings.SplitN(rest, "=", 2)[0]
+	e.env = setEnv(e.env, key, rest)
+	return nil
+}
+
+func setEnv(env []string, key, kv string) []string {
+	prefix := key + "="
+	for i, e := range env {
+		if strings.HasPrefix(e, prefix) {
+			env[i] = kv
+			return env
+		}
+	}
+	return append(env, kv)
+}
+
+func (e *engine) cmdCd(rest string) error {
+	if rest == "" {
+		return fmt.Errorf("cd requires a directory")
+	}
+	dir := rest
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(e.cwd, dir)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	e.cwd = dir
+	return nil
+}
+
+func (e *engine) cmdExec(rest string, negate bool) error {
+	args, err := splitArgs(rest)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("exec requires a command")
+	}
+
+	task := goexec.ExecTask{
+		Command: args[0],
+		Args:    args[1:],
+		Cwd:     e.cwd,
+		Env:     e.env,
+	}
+
+	if e.nextStdin != "" {
+		f, err := os.Open(filepath.Join(e.cwd, e.nextStdin))
+		if err != nil {
+			return fmt.Errorf("stdin: %w", err)
+		}
+		defer f.Close()
+		task.Stdin = f
+		e.nextStdin = ""
+	}
+
+	result, err := task.Execute(context.Background())
+	e.lastResult = result
+	e.lastErr = err
+
+	failed := err != nil || result.ExitCode != 0
+	if negate && !failed {
+		return fmt.Errorf("exec %s: expected failure but command succeeded", args[0])
+	}
+	if !negate && failed {
+		if err != nil {
+			return fmt.Errorf("exec %s: %w", args[0], err)
+		}
+		return fmt.Errorf("exec %s: exit code %d", args[0], result.ExitCode)
+	}
+	return nil
+}
+
+func (e *engine) cmdStdin(rest string) error {
+	if rest == "" {
+		return fmt.Errorf("stdin requires a file")
+	}
+	e.nextStdin = rest
+	return nil
+}
+
+func (e *engine) cmdStdout(rest string, negate bool) error {
+	return matchOutput("stdout", e.lastResult.Stdout, rest, negate)
+}
+
+func (e *engine) cmdStderr(rest string, negate bool) error {
+	return matchOutput("stderr", e.lastResult.Stderr, rest, negate)
+}
+
+// matchOutput asserts that output matches the regexp given in rest. rest is
+// split like an exec line's arguments so that a pattern containing spaces
+// can be quoted (e.g. stdout 'hello world'); the unquoted first argument is
+// the pattern.
+func matchOutput(name, output, rest string, negate bool) error {
+	args, err := splitArgs(rest)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("%s requires a regexp", name)
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("%s: unexpected extra arguments %q; quote the pattern if it contains spaces", name, args[1:])
+	}
+	pattern := args[0]
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	matched := re.MatchString(output)
+	if matched == negate {
+		return fmt.Errorf("%s %q does not match output %q", name, pattern, output)
+	}
+	return nil
+}
+
+func (e *engine) cmdWait(rest string) error {
+	d, err := time.ParseDuration(rest)
+	if err != nil {
+		return fmt.Errorf("wait: %w", err)
+	}
+	time.Sleep(d)
+	return nil
+}
+
+// splitCommand splits a script line into its leading command word and the
+// remainder of the line.
+func splitCommand(line string) (cmd, rest string) {
+	parts := strings.SplitN(line, " ", 2)
+	cmd = parts[0]
+	if len(parts) > 1 {
+		rest = strings.TrimSpace(parts[1])
+	}
+	return cmd, rest
+}
+
+// splitArgs splits a command line into arguments, honoring single and
+// double quoted substrings so that "exec echo \"hello world\"" produces a
+// single argument.
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inSingle, inDouble := false, false
+	hasCur := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+			hasCur = true
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+			hasCur = true
+		case c == ' ' && !inSingle && !inDouble:
+			if hasCur {
+				args = append(args, cur.String())
+				cur.Reset()
+				hasCur = false
+			}
+		default:
+			cur.WriteByte(c)
+			hasCur = true
+		}
+	}
+	if inSingle || inDouble {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if hasCur {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,50 @@
+package scripttest
+
+import "testing"
+
+func Test_Run_Echo(t *testing.T) {
+	Run(t, "testdata/echo.txt")
+}
+
+func Test_splitArgs(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{`echo hello world`, []string{"echo", "hello", "world"}},
+		{`echo "hello world"`, []string{"echo", "hello world"}},
+		{`echo 'hello world'`, []string{"echo", "hello world"}},
+		{``, nil},
+	}
+
+	for _, c := range cases {
+		got, err := splitArgs(c.in)
+		if err != nil {
+			t.Fatalf("splitArgs(%q): unexpected error: %v", c.in, err)
+		}
+		if len(got) != len(c.want) {
+			t.Fatalf("splitArgs(%q) = %q, want %q", c.in, got, c.want)
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Fatalf("splitArgs(%q) = %q, want %q", c.in, got, c.want)
+			}
+		}
+	}
+}
+
+func Test_splitArgs_unterminatedQuote(t *testing.T) {
+	if _, err := splitArgs(`echo "hello`); err == nil {
+		t.Fatalf("expected an error for an unterminated quote")
+	}
+}
+
+func Test_matchOutput_unquotedMultiWordPattern(t *testing.T) {
+	// A pattern with spaces must be quoted; an unquoted one must be
+	// rejected rather than silently truncated to its first word, which
+	// would let an unrelated output substring-match and falsely pass.
+	err := matchOutput("stdout", "exit 1\n", "exit code zero", false)
+	if err == nil {
+		t.Fatalf("expected an error for an unquoted multi-word pattern")
+	}
+}